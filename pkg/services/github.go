@@ -3,15 +3,18 @@ package services
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	texttemplate "text/template"
 	"unicode/utf8"
 
 	"github.com/bradleyfalzon/ghinstallation/v2"
-	"github.com/google/go-github/v41/github"
+	"github.com/google/go-github/v57/github"
 	log "github.com/sirupsen/logrus"
 	giturls "github.com/whilp/git-urls"
 
@@ -24,10 +27,69 @@ var (
 )
 
 type GitHubOptions struct {
-	AppID             int64  `json:"appID"`
-	InstallationID    int64  `json:"installationID"`
-	PrivateKey        string `json:"privateKey"`
-	EnterpriseBaseURL string `json:"enterpriseBaseURL"`
+	AppID             int64                 `json:"appID"`
+	InstallationID    int64                 `json:"installationID"`
+	PrivateKey        string                `json:"privateKey"`
+	EnterpriseBaseURL string                `json:"enterpriseBaseURL"`
+	RetryPolicy       *httputil.RetryPolicy `json:"retryPolicy,omitempty"`
+}
+
+// GitHubPullRequestComment posts a comment on the pull request(s) associated
+// with the notification's revision.
+type GitHubPullRequestComment struct {
+	Content string `json:"content,omitempty"`
+}
+
+// GitHubPullRequestReview submits a review on the pull request(s) associated
+// with the notification's revision.
+type GitHubPullRequestReview struct {
+	Event string `json:"event,omitempty"` // APPROVE, REQUEST_CHANGES or COMMENT
+	Body  string `json:"body,omitempty"`
+}
+
+// GitHubCheckRun creates a check run for the notification's revision, or
+// updates it when ID is set.
+type GitHubCheckRun struct {
+	ID         int64  `json:"id,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Status     string `json:"status,omitempty"`     // queued, in_progress, completed
+	Conclusion string `json:"conclusion,omitempty"` // required when Status is completed
+	Summary    string `json:"summary,omitempty"`
+	Text       string `json:"text,omitempty"`
+	DetailsURL string `json:"detailsURL,omitempty"`
+}
+
+// GitHubDeployment creates a deployment for the notification's revision.
+type GitHubDeployment struct {
+	Environment string `json:"environment,omitempty"`
+	Description string `json:"description,omitempty"`
+	Task        string `json:"task,omitempty"`
+}
+
+// GitHubDeploymentStatus updates the status of the deployment identified by
+// DeploymentID.
+type GitHubDeploymentStatus struct {
+	DeploymentID int64  `json:"deploymentID,omitempty"`
+	State        string `json:"state,omitempty"`
+	Description  string `json:"description,omitempty"`
+	Environment  string `json:"environment,omitempty"`
+	LogURL       string `json:"logURL,omitempty"`
+}
+
+// GitHubIssueComment posts a comment on an issue. Unlike
+// GitHubPullRequestComment, the issue is addressed directly by number rather
+// than resolved from the notification's revision.
+type GitHubIssueComment struct {
+	Number  int    `json:"number,omitempty"`
+	Content string `json:"content,omitempty"`
+}
+
+// GitHubWorkflowDispatch triggers a GitHub Actions workflow_dispatch event,
+// letting an Argo notification kick off a downstream Actions workflow.
+type GitHubWorkflowDispatch struct {
+	WorkflowFileName string            `json:"workflowFileName,omitempty"`
+	Ref              string            `json:"ref,omitempty"`
+	Inputs           map[string]string `json:"inputs,omitempty"`
 }
 
 type GitHubNotification struct {
@@ -36,6 +98,14 @@ type GitHubNotification struct {
 	TargetURL string `json:"targetURL,omitempty"`
 	RepoURL   string `json:"repoURL,omitempty"`
 	Revision  string `json:"revision,omitempty"`
+
+	PullRequestComment *GitHubPullRequestComment `json:"pullRequestComment,omitempty"`
+	PullRequestReview  *GitHubPullRequestReview  `json:"pullRequestReview,omitempty"`
+	CheckRun           *GitHubCheckRun           `json:"checkRun,omitempty"`
+	Deployment         *GitHubDeployment         `json:"deployment,omitempty"`
+	DeploymentStatus   *GitHubDeploymentStatus   `json:"deploymentStatus,omitempty"`
+	IssueComment       *GitHubIssueComment       `json:"issueComment,omitempty"`
+	WorkflowDispatch   *GitHubWorkflowDispatch   `json:"workflowDispatch,omitempty"`
 }
 
 const (
@@ -43,13 +113,32 @@ const (
 	defaultRevisionTemplate = "{{.app.status.operationState.syncResult.revision}}"
 )
 
+// templateField parses text as a named template, wrapping any parse error
+// with the field name so GetTemplater failures are easy to trace back to a
+// single misconfigured sub-config.
+func templateField(name, field, text string, f texttemplate.FuncMap) (*texttemplate.Template, error) {
+	tmpl, err := texttemplate.New(name).Funcs(f).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s template: %w", field, err)
+	}
+	return tmpl, nil
+}
+
+func execTemplate(tmpl *texttemplate.Template, vars map[string]interface{}) (string, error) {
+	var data bytes.Buffer
+	if err := tmpl.Execute(&data, vars); err != nil {
+		return "", err
+	}
+	return data.String(), nil
+}
+
 func (g *GitHubNotification) GetTemplater(name string, f texttemplate.FuncMap) (Templater, error) {
 
 	repoURLtemplate := defaultRepoURLtemplate
 	if g.RepoURL != "" {
 		repoURLtemplate = g.RepoURL
 	}
-	repoURL, err := texttemplate.New(name).Funcs(f).Parse(repoURLtemplate)
+	repoURL, err := templateField(name, "repoURL", repoURLtemplate, f)
 	if err != nil {
 		return nil, err
 	}
@@ -58,74 +147,337 @@ func (g *GitHubNotification) GetTemplater(name string, f texttemplate.FuncMap) (
 	if g.Revision != "" {
 		revisionTemplate = g.Revision
 	}
-	revision, err := texttemplate.New(name).Funcs(f).Parse(revisionTemplate)
+	revision, err := templateField(name, "revision", revisionTemplate, f)
 	if err != nil {
 		return nil, err
 	}
 
-	state, err := texttemplate.New(name).Funcs(f).Parse(g.State)
+	state, err := templateField(name, "state", g.State, f)
 	if err != nil {
 		return nil, err
 	}
 
-	label, err := texttemplate.New(name).Funcs(f).Parse(g.Label)
+	label, err := templateField(name, "label", g.Label, f)
 	if err != nil {
 		return nil, err
 	}
 
-	targetURL, err := texttemplate.New(name).Funcs(f).Parse(g.TargetURL)
+	targetURL, err := templateField(name, "targetURL", g.TargetURL, f)
 	if err != nil {
 		return nil, err
 	}
 
+	var prComment struct{ content *texttemplate.Template }
+	if g.PullRequestComment != nil {
+		if prComment.content, err = templateField(name, "pullRequestComment.content", g.PullRequestComment.Content, f); err != nil {
+			return nil, err
+		}
+	}
+
+	var prReview struct{ event, body *texttemplate.Template }
+	if g.PullRequestReview != nil {
+		if prReview.event, err = templateField(name, "pullRequestReview.event", g.PullRequestReview.Event, f); err != nil {
+			return nil, err
+		}
+		if prReview.body, err = templateField(name, "pullRequestReview.body", g.PullRequestReview.Body, f); err != nil {
+			return nil, err
+		}
+	}
+
+	var checkRun struct{ name, status, conclusion, summary, text, detailsURL *texttemplate.Template }
+	if g.CheckRun != nil {
+		if checkRun.name, err = templateField(name, "checkRun.name", g.CheckRun.Name, f); err != nil {
+			return nil, err
+		}
+		if checkRun.status, err = templateField(name, "checkRun.status", g.CheckRun.Status, f); err != nil {
+			return nil, err
+		}
+		if checkRun.conclusion, err = templateField(name, "checkRun.conclusion", g.CheckRun.Conclusion, f); err != nil {
+			return nil, err
+		}
+		if checkRun.summary, err = templateField(name, "checkRun.summary", g.CheckRun.Summary, f); err != nil {
+			return nil, err
+		}
+		if checkRun.text, err = templateField(name, "checkRun.text", g.CheckRun.Text, f); err != nil {
+			return nil, err
+		}
+		if checkRun.detailsURL, err = templateField(name, "checkRun.detailsURL", g.CheckRun.DetailsURL, f); err != nil {
+			return nil, err
+		}
+	}
+
+	var deployment struct{ environment, description, task *texttemplate.Template }
+	if g.Deployment != nil {
+		if deployment.environment, err = templateField(name, "deployment.environment", g.Deployment.Environment, f); err != nil {
+			return nil, err
+		}
+		if deployment.description, err = templateField(name, "deployment.description", g.Deployment.Description, f); err != nil {
+			return nil, err
+		}
+		if deployment.task, err = templateField(name, "deployment.task", g.Deployment.Task, f); err != nil {
+			return nil, err
+		}
+	}
+
+	var deploymentStatus struct{ state, description, environment, logURL *texttemplate.Template }
+	if g.DeploymentStatus != nil {
+		if deploymentStatus.state, err = templateField(name, "deploymentStatus.state", g.DeploymentStatus.State, f); err != nil {
+			return nil, err
+		}
+		if deploymentStatus.description, err = templateField(name, "deploymentStatus.description", g.DeploymentStatus.Description, f); err != nil {
+			return nil, err
+		}
+		if deploymentStatus.environment, err = templateField(name, "deploymentStatus.environment", g.DeploymentStatus.Environment, f); err != nil {
+			return nil, err
+		}
+		if deploymentStatus.logURL, err = templateField(name, "deploymentStatus.logURL", g.DeploymentStatus.LogURL, f); err != nil {
+			return nil, err
+		}
+	}
+
+	var issueComment struct{ content *texttemplate.Template }
+	if g.IssueComment != nil {
+		if issueComment.content, err = templateField(name, "issueComment.content", g.IssueComment.Content, f); err != nil {
+			return nil, err
+		}
+	}
+
+	var workflowDispatch struct {
+		workflowFileName, ref *texttemplate.Template
+		inputs                map[string]*texttemplate.Template
+	}
+	if g.WorkflowDispatch != nil {
+		if workflowDispatch.workflowFileName, err = templateField(name, "workflowDispatch.workflowFileName", g.WorkflowDispatch.WorkflowFileName, f); err != nil {
+			return nil, err
+		}
+		if workflowDispatch.ref, err = templateField(name, "workflowDispatch.ref", g.WorkflowDispatch.Ref, f); err != nil {
+			return nil, err
+		}
+		workflowDispatch.inputs = make(map[string]*texttemplate.Template, len(g.WorkflowDispatch.Inputs))
+		for key, value := range g.WorkflowDispatch.Inputs {
+			if workflowDispatch.inputs[key], err = templateField(name, fmt.Sprintf("workflowDispatch.inputs.%s", key), value, f); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	return func(notification *Notification, vars map[string]interface{}) error {
 		if notification.GitHub == nil {
 			notification.GitHub = &GitHubNotification{}
 		}
 
-		var repoData bytes.Buffer
-		if err := repoURL.Execute(&repoData, vars); err != nil {
+		var err error
+		if notification.GitHub.RepoURL, err = execTemplate(repoURL, vars); err != nil {
 			return err
 		}
-		notification.GitHub.RepoURL = repoData.String()
-
-		var revisionData bytes.Buffer
-		if err := revision.Execute(&revisionData, vars); err != nil {
+		if notification.GitHub.Revision, err = execTemplate(revision, vars); err != nil {
 			return err
 		}
-		notification.GitHub.Revision = revisionData.String()
-
-		var stateData bytes.Buffer
-		if err := state.Execute(&stateData, vars); err != nil {
+		if notification.GitHub.State, err = execTemplate(state, vars); err != nil {
 			return err
 		}
-		notification.GitHub.State = stateData.String()
-
-		var labelData bytes.Buffer
-		if err := label.Execute(&labelData, vars); err != nil {
+		if notification.GitHub.Label, err = execTemplate(label, vars); err != nil {
 			return err
 		}
-		notification.GitHub.Label = labelData.String()
-
-		var targetData bytes.Buffer
-		if err := targetURL.Execute(&targetData, vars); err != nil {
+		if notification.GitHub.TargetURL, err = execTemplate(targetURL, vars); err != nil {
 			return err
 		}
-		notification.GitHub.TargetURL = targetData.String()
+
+		if g.PullRequestComment != nil {
+			content, err := execTemplate(prComment.content, vars)
+			if err != nil {
+				return err
+			}
+			notification.GitHub.PullRequestComment = &GitHubPullRequestComment{Content: content}
+		}
+
+		if g.PullRequestReview != nil {
+			event, err := execTemplate(prReview.event, vars)
+			if err != nil {
+				return err
+			}
+			body, err := execTemplate(prReview.body, vars)
+			if err != nil {
+				return err
+			}
+			notification.GitHub.PullRequestReview = &GitHubPullRequestReview{Event: event, Body: body}
+		}
+
+		if g.CheckRun != nil {
+			c := &GitHubCheckRun{ID: g.CheckRun.ID}
+			if c.Name, err = execTemplate(checkRun.name, vars); err != nil {
+				return err
+			}
+			if c.Status, err = execTemplate(checkRun.status, vars); err != nil {
+				return err
+			}
+			if c.Conclusion, err = execTemplate(checkRun.conclusion, vars); err != nil {
+				return err
+			}
+			if c.Summary, err = execTemplate(checkRun.summary, vars); err != nil {
+				return err
+			}
+			if c.Text, err = execTemplate(checkRun.text, vars); err != nil {
+				return err
+			}
+			if c.DetailsURL, err = execTemplate(checkRun.detailsURL, vars); err != nil {
+				return err
+			}
+			notification.GitHub.CheckRun = c
+		}
+
+		if g.Deployment != nil {
+			d := &GitHubDeployment{}
+			if d.Environment, err = execTemplate(deployment.environment, vars); err != nil {
+				return err
+			}
+			if d.Description, err = execTemplate(deployment.description, vars); err != nil {
+				return err
+			}
+			if d.Task, err = execTemplate(deployment.task, vars); err != nil {
+				return err
+			}
+			notification.GitHub.Deployment = d
+		}
+
+		if g.DeploymentStatus != nil {
+			ds := &GitHubDeploymentStatus{DeploymentID: g.DeploymentStatus.DeploymentID}
+			if ds.State, err = execTemplate(deploymentStatus.state, vars); err != nil {
+				return err
+			}
+			if ds.Description, err = execTemplate(deploymentStatus.description, vars); err != nil {
+				return err
+			}
+			if ds.Environment, err = execTemplate(deploymentStatus.environment, vars); err != nil {
+				return err
+			}
+			if ds.LogURL, err = execTemplate(deploymentStatus.logURL, vars); err != nil {
+				return err
+			}
+			notification.GitHub.DeploymentStatus = ds
+		}
+
+		if g.IssueComment != nil {
+			content, err := execTemplate(issueComment.content, vars)
+			if err != nil {
+				return err
+			}
+			notification.GitHub.IssueComment = &GitHubIssueComment{Number: g.IssueComment.Number, Content: content}
+		}
+
+		if g.WorkflowDispatch != nil {
+			wd := &GitHubWorkflowDispatch{Inputs: make(map[string]string, len(workflowDispatch.inputs))}
+			if wd.WorkflowFileName, err = execTemplate(workflowDispatch.workflowFileName, vars); err != nil {
+				return err
+			}
+			if wd.Ref, err = execTemplate(workflowDispatch.ref, vars); err != nil {
+				return err
+			}
+			for key, tmpl := range workflowDispatch.inputs {
+				if wd.Inputs[key], err = execTemplate(tmpl, vars); err != nil {
+					return err
+				}
+			}
+			notification.GitHub.WorkflowDispatch = wd
+		}
 
 		return nil
 	}, nil
 }
 
-func NewGitHubService(opts GitHubOptions) (NotificationService, error) {
+// GitHubAppTransportCache memoizes ghinstallation transports keyed by
+// (AppID, InstallationID, EnterpriseBaseURL, PrivateKey fingerprint,
+// RetryPolicy), so repeatedly
+// instantiating GitHub services for the same GitHub App installation (as
+// notification controllers do when reconciling many applications) doesn't
+// re-do JWT signing and token exchange bookkeeping on every call.
+type GitHubAppTransportCache struct {
+	mu   sync.Mutex
+	byID map[string]*ghinstallation.Transport
+}
+
+// NewGitHubAppTransportCache returns an empty transport cache.
+func NewGitHubAppTransportCache() *GitHubAppTransportCache {
+	return &GitHubAppTransportCache{byID: map[string]*ghinstallation.Transport{}}
+}
+
+// defaultGitHubAppTransportCache backs NewGitHubService so that services
+// created independently for the same installation share one transport.
+var defaultGitHubAppTransportCache = NewGitHubAppTransportCache()
+
+// transportCacheKey includes the retry policy alongside the installation
+// identity: opts.RetryPolicy selects runtime behavior of the cached
+// transport itself, so two calls for the same installation that ask for
+// different policies must not silently share one.
+func transportCacheKey(opts GitHubOptions) string {
+	fingerprint := sha256.Sum256([]byte(opts.PrivateKey))
+	policy := retryPolicyOrDefault(opts)
+	return fmt.Sprintf("%d/%d/%s/%x/%+v", opts.AppID, opts.InstallationID, opts.EnterpriseBaseURL, fingerprint, policy)
+}
+
+func retryPolicyOrDefault(opts GitHubOptions) httputil.RetryPolicy {
+	if opts.RetryPolicy != nil {
+		return *opts.RetryPolicy
+	}
+	return httputil.DefaultRetryPolicy
+}
+
+// newGitHubTransport builds the logging-and-retry RoundTripper chain used to
+// reach opts.EnterpriseBaseURL (or github.com). Both NewGitHubService and
+// InstallationTokenSource build transports through this helper so that a
+// transport cached by one always has the logging/retry behavior the other
+// would have configured for the same opts, instead of leaving it to whichever
+// caller happens to populate the cache first.
+func newGitHubTransport(opts GitHubOptions) http.RoundTripper {
 	url := "https://api.github.com"
 	if opts.EnterpriseBaseURL != "" {
 		url = opts.EnterpriseBaseURL
 	}
 
-	tr := httputil.NewLoggingRoundTripper(
-		httputil.NewTransport(url, false), log.WithField("service", "github"))
-	itr, err := ghinstallation.New(tr, opts.AppID, opts.InstallationID, []byte(opts.PrivateKey))
+	logEntry := log.WithField("service", "github")
+	return httputil.NewLoggingRoundTripper(
+		httputil.NewRetryRoundTripper(httputil.NewTransport(url, false), retryPolicyOrDefault(opts), logEntry), logEntry)
+}
+
+// Get returns the cached transport for opts, creating and caching one using
+// base as its underlying RoundTripper if none exists yet.
+func (c *GitHubAppTransportCache) Get(opts GitHubOptions, base http.RoundTripper) (*ghinstallation.Transport, error) {
+	key := transportCacheKey(opts)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if itr, ok := c.byID[key]; ok {
+		return itr, nil
+	}
+
+	itr, err := ghinstallation.New(base, opts.AppID, opts.InstallationID, []byte(opts.PrivateKey))
+	if err != nil {
+		return nil, err
+	}
+	if opts.EnterpriseBaseURL != "" {
+		itr.BaseURL = opts.EnterpriseBaseURL
+	}
+
+	c.byID[key] = itr
+	return itr, nil
+}
+
+// InstallationTokenSource returns a function that yields a valid installation
+// access token for opts on each call, reusing the cached transport so other
+// services (e.g. a GitHub Discussions or Projects notifier) can authenticate
+// without duplicating JWT signing.
+func (c *GitHubAppTransportCache) InstallationTokenSource(opts GitHubOptions) (func(ctx context.Context) (string, error), error) {
+	itr, err := c.Get(opts, newGitHubTransport(opts))
+	if err != nil {
+		return nil, err
+	}
+	return itr.Token, nil
+}
+
+func NewGitHubService(opts GitHubOptions) (NotificationService, error) {
+	logEntry := log.WithField("service", "github")
+	itr, err := defaultGitHubAppTransportCache.Get(opts, newGitHubTransport(opts))
 	if err != nil {
 		return nil, err
 	}
@@ -134,7 +486,6 @@ func NewGitHubService(opts GitHubOptions) (NotificationService, error) {
 	if opts.EnterpriseBaseURL == "" {
 		client = github.NewClient(&http.Client{Transport: itr})
 	} else {
-		itr.BaseURL = opts.EnterpriseBaseURL
 		client, err = github.NewEnterpriseClient(opts.EnterpriseBaseURL, "", &http.Client{Transport: itr})
 		if err != nil {
 			return nil, err
@@ -142,15 +493,19 @@ func NewGitHubService(opts GitHubOptions) (NotificationService, error) {
 	}
 
 	return &gitHubService{
-		opts:   opts,
-		client: client,
+		opts:      opts,
+		client:    client,
+		repoNames: defaultRepoFullNameCache,
+		log:       logEntry,
 	}, nil
 }
 
 type gitHubService struct {
 	opts GitHubOptions
 
-	client *github.Client
+	client    *github.Client
+	repoNames *repoFullNameCache
+	log       *log.Entry
 }
 
 func trunc(message string, n int) string {
@@ -160,18 +515,110 @@ func trunc(message string, n int) string {
 	return message
 }
 
-func fullNameByRepoURL(rawURL string) string {
+// fullNameByRepoURL extracts the "owner/repo" full name from a git remote
+// URL. It returns an error, rather than panicking, when rawURL cannot be
+// parsed or doesn't contain at least an owner and a repo path segment, so
+// callers can fall back to resolving the repository via the REST API.
+func fullNameByRepoURL(rawURL string) (string, error) {
 	parsed, err := giturls.Parse(rawURL)
 	if err != nil {
-		panic(err)
+		return "", fmt.Errorf("failed to parse repo URL %q: %w", rawURL, err)
 	}
 
 	path := gitSuffix.ReplaceAllString(parsed.Path, "")
-	if pathParts := text.SplitRemoveEmpty(path, "/"); len(pathParts) >= 2 {
-		return strings.Join(pathParts[:2], "/")
+	pathParts := text.SplitRemoveEmpty(path, "/")
+	if len(pathParts) < 2 {
+		return "", fmt.Errorf("could not determine owner and repo from URL %q", rawURL)
 	}
 
-	return path
+	return strings.Join(pathParts[:2], "/"), nil
+}
+
+// repoCacheKey scopes a cached full name to the GitHub host it was resolved
+// against, so a bare numeric RepoURL - which is only unique per host - can't
+// collide across two installations pointed at different hosts (e.g. one at
+// github.com, another at a GitHub Enterprise Server).
+type repoCacheKey struct {
+	enterpriseBaseURL string
+	rawURL            string
+}
+
+// repoFullNameCache memoizes "owner/repo" full names resolved from RepoURL
+// values, so repeatedly notifying on the same (unparseable or bare-ID)
+// RepoURL doesn't re-hit the REST API under rate-limit pressure.
+type repoFullNameCache struct {
+	mu        sync.Mutex
+	byRepoURL map[repoCacheKey]string
+}
+
+func newRepoFullNameCache() *repoFullNameCache {
+	return &repoFullNameCache{byRepoURL: map[repoCacheKey]string{}}
+}
+
+// defaultRepoFullNameCache is shared across all gitHubService instances,
+// mirroring defaultGitHubAppTransportCache: notification controllers
+// reconcile thousands of applications and repeatedly instantiate services,
+// so a cache scoped to a single instance would be recreated empty on every
+// call and never actually avoid repeated REST lookups.
+var defaultRepoFullNameCache = newRepoFullNameCache()
+
+func (c *repoFullNameCache) get(enterpriseBaseURL, rawURL string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fullName, ok := c.byRepoURL[repoCacheKey{enterpriseBaseURL, rawURL}]
+	return fullName, ok
+}
+
+func (c *repoFullNameCache) set(enterpriseBaseURL, rawURL, fullName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.byRepoURL[repoCacheKey{enterpriseBaseURL, rawURL}] = fullName
+}
+
+// resolveRepoFullName determines the owner and repo for rawURL. It first
+// tries to parse rawURL as a git remote URL; if that fails and rawURL is a
+// bare numeric repository ID, it resolves the repository through the REST API
+// by that ID. Any other unparseable rawURL (e.g. a templated variable that
+// didn't render) returns the parse error; no REST search by URL is made.
+// Results are cached by rawURL to avoid repeated REST calls.
+func (g gitHubService) resolveRepoFullName(ctx context.Context, rawURL string) (owner, repo string, err error) {
+	if fullName, ok := g.repoNames.get(g.opts.EnterpriseBaseURL, rawURL); ok {
+		parts := strings.SplitN(fullName, "/", 2)
+		return parts[0], parts[1], nil
+	}
+
+	fullName, parseErr := fullNameByRepoURL(rawURL)
+	if parseErr != nil {
+		id, convErr := strconv.ParseInt(strings.TrimSpace(rawURL), 10, 64)
+		if convErr != nil {
+			return "", "", parseErr
+		}
+
+		repository, _, err := g.client.Repositories.GetByID(ctx, id)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to resolve repository by ID %d: %w", id, err)
+		}
+		fullName = repository.GetFullName()
+	}
+
+	g.repoNames.set(g.opts.EnterpriseBaseURL, rawURL, fullName)
+	parts := strings.SplitN(fullName, "/", 2)
+	return parts[0], parts[1], nil
+}
+
+// pullRequestNumbersForRevision resolves the pull request(s) associated with
+// a commit, since PR-scoped endpoints are addressed by PR number rather than
+// by revision.
+func (g gitHubService) pullRequestNumbersForRevision(ctx context.Context, owner, repo, revision string) ([]int, error) {
+	prs, _, err := g.client.PullRequests.ListPullRequestsWithCommit(ctx, owner, repo, revision, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull requests for revision %s: %w", revision, err)
+	}
+	numbers := make([]int, 0, len(prs))
+	for _, pr := range prs {
+		numbers = append(numbers, pr.GetNumber())
+	}
+	return numbers, nil
 }
 
 func (g gitHubService) Send(notification Notification, _ Destination) error {
@@ -179,23 +626,144 @@ func (g gitHubService) Send(notification Notification, _ Destination) error {
 		return fmt.Errorf("config is empty")
 	}
 
-	u := strings.Split(fullNameByRepoURL(notification.GitHub.RepoURL), "/")
-	// maximum is 140 characters
-	description := trunc(notification.Message, 140)
-	_, _, err := g.client.Repositories.CreateStatus(
-		context.Background(),
-		u[0],
-		u[1],
-		notification.GitHub.Revision,
-		&github.RepoStatus{
-			State:       &notification.GitHub.State,
-			Description: &description,
-			Context:     &notification.GitHub.Label,
-			TargetURL:   &notification.GitHub.TargetURL,
-		},
-	)
+	gh := notification.GitHub
+	ctx := context.Background()
+	owner, repo, err := g.resolveRepoFullName(ctx, gh.RepoURL)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to resolve repository for %q: %w", gh.RepoURL, err)
+	}
+
+	// State is the only field that selects the legacy commit-status mode; leave
+	// it unset to use GitHub exclusively through the newer modes below (e.g. a
+	// standalone PR comment or workflow dispatch).
+	if gh.State != "" {
+		// maximum is 140 characters
+		description := trunc(notification.Message, 140)
+		if _, _, err := g.client.Repositories.CreateStatus(ctx, owner, repo, gh.Revision, &github.RepoStatus{
+			State:       &gh.State,
+			Description: &description,
+			Context:     &gh.Label,
+			TargetURL:   &gh.TargetURL,
+		}); err != nil {
+			return fmt.Errorf("failed to create commit status: %w", err)
+		}
+	}
+
+	if gh.PullRequestComment != nil || gh.PullRequestReview != nil {
+		numbers, err := g.pullRequestNumbersForRevision(ctx, owner, repo, gh.Revision)
+		if err != nil {
+			return fmt.Errorf("failed to resolve pull requests for revision %s: %w", gh.Revision, err)
+		}
+		for _, number := range numbers {
+			if c := gh.PullRequestComment; c != nil {
+				if _, _, err := g.client.Issues.CreateComment(ctx, owner, repo, number, &github.IssueComment{Body: &c.Content}); err != nil {
+					return fmt.Errorf("failed to comment on pull request #%d: %w", number, err)
+				}
+			}
+			if r := gh.PullRequestReview; r != nil {
+				if _, _, err := g.client.PullRequests.CreateReview(ctx, owner, repo, number, &github.PullRequestReviewRequest{
+					CommitID: &gh.Revision,
+					Body:     &r.Body,
+					Event:    &r.Event,
+				}); err != nil {
+					return fmt.Errorf("failed to review pull request #%d: %w", number, err)
+				}
+			}
+		}
+	}
+
+	if c := gh.CheckRun; c != nil {
+		// Output and DetailsURL are only set when the notification actually
+		// configures them. UpdateCheckRun in particular is commonly used to
+		// move an existing check from in_progress to completed without
+		// repeating its summary/text, and an unconditional pointer here
+		// would blank out that prior state instead of leaving it alone.
+		var output *github.CheckRunOutput
+		if c.Summary != "" || c.Text != "" {
+			output = &github.CheckRunOutput{
+				Title:   &c.Name,
+				Summary: &c.Summary,
+				Text:    &c.Text,
+			}
+		}
+
+		if c.ID != 0 {
+			opts := github.UpdateCheckRunOptions{
+				Name:   c.Name,
+				Output: output,
+			}
+			if c.DetailsURL != "" {
+				opts.DetailsURL = &c.DetailsURL
+			}
+			if c.Status != "" {
+				opts.Status = &c.Status
+			}
+			if c.Conclusion != "" {
+				opts.Conclusion = &c.Conclusion
+			}
+			if _, _, err := g.client.Checks.UpdateCheckRun(ctx, owner, repo, c.ID, opts); err != nil {
+				return fmt.Errorf("failed to update check run %q: %w", c.Name, err)
+			}
+		} else {
+			opts := github.CreateCheckRunOptions{
+				Name:    c.Name,
+				HeadSHA: gh.Revision,
+				Output:  output,
+			}
+			if c.DetailsURL != "" {
+				opts.DetailsURL = &c.DetailsURL
+			}
+			if c.Status != "" {
+				opts.Status = &c.Status
+			}
+			if c.Conclusion != "" {
+				opts.Conclusion = &c.Conclusion
+			}
+			if _, _, err := g.client.Checks.CreateCheckRun(ctx, owner, repo, opts); err != nil {
+				return fmt.Errorf("failed to create check run %q: %w", c.Name, err)
+			}
+		}
+	}
+
+	if d := gh.Deployment; d != nil {
+		if _, _, err := g.client.Repositories.CreateDeployment(ctx, owner, repo, &github.DeploymentRequest{
+			Ref:         &gh.Revision,
+			Task:        &d.Task,
+			Environment: &d.Environment,
+			Description: &d.Description,
+		}); err != nil {
+			return fmt.Errorf("failed to create deployment: %w", err)
+		}
+	}
+
+	if ds := gh.DeploymentStatus; ds != nil {
+		if _, _, err := g.client.Repositories.CreateDeploymentStatus(ctx, owner, repo, ds.DeploymentID, &github.DeploymentStatusRequest{
+			State:       &ds.State,
+			Description: &ds.Description,
+			Environment: &ds.Environment,
+			LogURL:      &ds.LogURL,
+		}); err != nil {
+			return fmt.Errorf("failed to create deployment status for deployment %d: %w", ds.DeploymentID, err)
+		}
+	}
+
+	if ic := gh.IssueComment; ic != nil {
+		if _, _, err := g.client.Issues.CreateComment(ctx, owner, repo, ic.Number, &github.IssueComment{Body: &ic.Content}); err != nil {
+			return fmt.Errorf("failed to comment on issue #%d: %w", ic.Number, err)
+		}
+	}
+
+	if wd := gh.WorkflowDispatch; wd != nil {
+		inputs := make(map[string]interface{}, len(wd.Inputs))
+		for key, value := range wd.Inputs {
+			inputs[key] = value
+		}
+		if _, err := g.client.Actions.CreateWorkflowDispatchEventByFileName(ctx, owner, repo, wd.WorkflowFileName, github.CreateWorkflowDispatchEventRequest{
+			Ref:    wd.Ref,
+			Inputs: inputs,
+		}); err != nil {
+			return fmt.Errorf("failed to dispatch workflow %q: %w", wd.WorkflowFileName, err)
+		}
 	}
 
 	return nil
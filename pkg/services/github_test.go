@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httputil "github.com/argoproj/notifications-engine/pkg/util/http"
+)
+
+func TestFullNameByRepoURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		rawURL  string
+		expect  string
+		wantErr bool
+	}{
+		{"https URL", "https://github.com/argoproj/notifications-engine", "argoproj/notifications-engine", false},
+		{"https URL with .git suffix", "https://github.com/argoproj/notifications-engine.git", "argoproj/notifications-engine", false},
+		{"ssh URL", "git@github.com:argoproj/notifications-engine.git", "argoproj/notifications-engine", false},
+		{"bare numeric ID", "12345", "", true},
+		{"missing repo segment", "https://github.com/argoproj", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fullName, err := fullNameByRepoURL(tt.rawURL)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expect, fullName)
+		})
+	}
+}
+
+func TestRepoFullNameCacheScopesByEnterpriseBaseURL(t *testing.T) {
+	c := newRepoFullNameCache()
+	c.set("", "42", "github-com-owner/repo")
+	c.set("https://ghe.example.com", "42", "ghe-owner/repo")
+
+	fullName, ok := c.get("", "42")
+	assert.True(t, ok)
+	assert.Equal(t, "github-com-owner/repo", fullName)
+
+	fullName, ok = c.get("https://ghe.example.com", "42")
+	assert.True(t, ok)
+	assert.Equal(t, "ghe-owner/repo", fullName)
+
+	_, ok = c.get("https://other.example.com", "42")
+	assert.False(t, ok)
+}
+
+func TestResolveRepoFullName(t *testing.T) {
+	t.Run("parses a git remote URL without hitting the REST API", func(t *testing.T) {
+		g := gitHubService{repoNames: newRepoFullNameCache()}
+		owner, repo, err := g.resolveRepoFullName(context.Background(), "https://github.com/argoproj/notifications-engine")
+		require.NoError(t, err)
+		assert.Equal(t, "argoproj", owner)
+		assert.Equal(t, "notifications-engine", repo)
+	})
+
+	t.Run("falls back to the REST API for a bare repository ID and caches the result", func(t *testing.T) {
+		var requests int
+		mux := http.NewServeMux()
+		mux.HandleFunc("/repositories/42", func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			fmt.Fprint(w, `{"full_name": "argoproj/notifications-engine"}`)
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		client := github.NewClient(nil)
+		baseURL, err := url.Parse(server.URL + "/")
+		require.NoError(t, err)
+		client.BaseURL = baseURL
+
+		g := gitHubService{client: client, repoNames: newRepoFullNameCache()}
+
+		owner, repo, err := g.resolveRepoFullName(context.Background(), "42")
+		require.NoError(t, err)
+		assert.Equal(t, "argoproj", owner)
+		assert.Equal(t, "notifications-engine", repo)
+		assert.Equal(t, 1, requests)
+
+		// A second call for the same raw URL should be served from the cache,
+		// not hit the REST API again.
+		owner, repo, err = g.resolveRepoFullName(context.Background(), "42")
+		require.NoError(t, err)
+		assert.Equal(t, "argoproj", owner)
+		assert.Equal(t, "notifications-engine", repo)
+		assert.Equal(t, 1, requests)
+	})
+
+	t.Run("returns an error when neither a git URL nor a numeric ID can be parsed", func(t *testing.T) {
+		g := gitHubService{repoNames: newRepoFullNameCache()}
+		_, _, err := g.resolveRepoFullName(context.Background(), "not-a-url-or-an-id")
+		assert.Error(t, err)
+	})
+}
+
+func TestTransportCacheKeyDiffersByRetryPolicy(t *testing.T) {
+	opts := GitHubOptions{AppID: 1, InstallationID: 2, PrivateKey: "key"}
+	defaultKey := transportCacheKey(opts)
+
+	withPolicy := opts
+	policy := httputil.RetryPolicy{MaxRetries: 10, BaseBackoff: time.Second, MaxBackoff: time.Minute}
+	withPolicy.RetryPolicy = &policy
+
+	assert.NotEqual(t, defaultKey, transportCacheKey(withPolicy))
+}
+
+func TestTransportCacheKeyStableForEquivalentPolicies(t *testing.T) {
+	opts := GitHubOptions{AppID: 1, InstallationID: 2, PrivateKey: "key"}
+
+	explicit := opts
+	defaultPolicy := httputil.DefaultRetryPolicy
+	explicit.RetryPolicy = &defaultPolicy
+
+	assert.Equal(t, transportCacheKey(opts), transportCacheKey(explicit))
+}
@@ -0,0 +1,121 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// RetryPolicy configures the retry count and backoff of the RoundTripper
+// returned by NewRetryRoundTripper. It is exported so
+// services built on this package's transport chain (GitHub, and any future
+// REST-based notifier) can expose the same retry knobs through their own
+// options structs.
+type RetryPolicy struct {
+	MaxRetries  int           `json:"maxRetries,omitempty"`
+	BaseBackoff time.Duration `json:"baseBackoff,omitempty"`
+	MaxBackoff  time.Duration `json:"maxBackoff,omitempty"`
+}
+
+// DefaultRetryPolicy is the policy GitHub services use when
+// GitHubOptions.RetryPolicy is unset. NewRetryRoundTripper does not apply it
+// on its own: it uses the policy it is given as is, and a zero policy
+// performs no retries.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries:  3,
+	BaseBackoff: time.Second,
+	MaxBackoff:  30 * time.Second,
+}
+
+type retryRoundTripper struct {
+	next   http.RoundTripper
+	policy RetryPolicy
+	log    *log.Entry
+}
+
+// NewRetryRoundTripper wraps next with retry-with-backoff handling for
+// secondary rate limits (a 403 or 429 carrying a Retry-After or
+// X-RateLimit-Remaining: 0 header) and 5xx server errors. It works purely
+// off the HTTP response, so it applies to any REST API reached through this
+// package's transport chain, not just GitHub.
+func NewRetryRoundTripper(next http.RoundTripper, policy RetryPolicy, logEntry *log.Entry) http.RoundTripper {
+	return &retryRoundTripper{next: next, policy: policy, log: logEntry}
+}
+
+func (rt *retryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	var bodyBytes []byte
+	if req.Body != nil {
+		var err error
+		bodyBytes, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var resp *http.Response
+	var err error
+	for attempt := 0; ; attempt++ {
+		if bodyBytes != nil {
+			req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+		}
+
+		resp, err = rt.next.RoundTrip(req)
+		if err != nil || !isRetryableResponse(resp) || attempt >= rt.policy.MaxRetries {
+			return resp, err
+		}
+
+		delay := retryDelay(resp, attempt, rt.policy)
+		if rt.log != nil {
+			rt.log.WithFields(log.Fields{
+				"url":     req.URL.String(),
+				"status":  resp.StatusCode,
+				"attempt": attempt + 1,
+				"delay":   delay,
+			}).Warn("retrying request after rate limit or server error")
+		}
+		resp.Body.Close()
+		time.Sleep(delay)
+	}
+}
+
+func isRetryableResponse(resp *http.Response) bool {
+	if resp == nil {
+		return false
+	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return true
+	}
+	if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests {
+		return resp.Header.Get("Retry-After") != "" || resp.Header.Get("X-RateLimit-Remaining") == "0"
+	}
+	return false
+}
+
+// retryDelay honors the server's Retry-After or X-RateLimit-Reset hints when
+// present, and otherwise backs off exponentially with jitter.
+func retryDelay(resp *http.Response, attempt int, policy RetryPolicy) time.Duration {
+	if ra := resp.Header.Get("Retry-After"); ra != "" {
+		if seconds, err := strconv.Atoi(ra); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	if reset := resp.Header.Get("X-RateLimit-Reset"); reset != "" {
+		if epoch, err := strconv.ParseInt(reset, 10, 64); err == nil {
+			if delay := time.Until(time.Unix(epoch, 0)); delay > 0 {
+				return delay
+			}
+		}
+	}
+
+	backoff := policy.BaseBackoff * time.Duration(int64(1)<<uint(attempt))
+	if backoff > policy.MaxBackoff {
+		backoff = policy.MaxBackoff
+	}
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff)/2+1))
+}
@@ -0,0 +1,82 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRetryableResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		resp   *http.Response
+		expect bool
+	}{
+		{"nil response", nil, false},
+		{"500", response(http.StatusInternalServerError, nil), true},
+		{"503", response(http.StatusServiceUnavailable, nil), true},
+		{"404", response(http.StatusNotFound, nil), false},
+		{"403 without rate limit headers", response(http.StatusForbidden, nil), false},
+		{"403 with Retry-After", response(http.StatusForbidden, http.Header{"Retry-After": {"1"}}), true},
+		{"403 with X-RateLimit-Remaining: 0", response(http.StatusForbidden, http.Header{"X-RateLimit-Remaining": {"0"}}), true},
+		{"429 with Retry-After", response(http.StatusTooManyRequests, http.Header{"Retry-After": {"1"}}), true},
+		{"429 without rate limit headers", response(http.StatusTooManyRequests, nil), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expect, isRetryableResponse(tt.resp))
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 3, BaseBackoff: time.Second, MaxBackoff: 10 * time.Second}
+
+	t.Run("honors Retry-After", func(t *testing.T) {
+		resp := response(http.StatusForbidden, http.Header{"Retry-After": {"5"}})
+		assert.Equal(t, 5*time.Second, retryDelay(resp, 0, policy))
+	})
+
+	t.Run("falls back to X-RateLimit-Reset", func(t *testing.T) {
+		reset := time.Now().Add(7 * time.Second)
+		resp := response(http.StatusForbidden, http.Header{"X-RateLimit-Reset": {formatEpoch(reset)}})
+		delay := retryDelay(resp, 0, policy)
+		assert.Greater(t, delay, 5*time.Second)
+		assert.LessOrEqual(t, delay, 7*time.Second)
+	})
+
+	t.Run("ignores a past X-RateLimit-Reset and backs off instead", func(t *testing.T) {
+		resp := response(http.StatusForbidden, http.Header{"X-RateLimit-Reset": {formatEpoch(time.Now().Add(-time.Minute))}})
+		delay := retryDelay(resp, 0, policy)
+		assert.Greater(t, delay, time.Duration(0))
+		assert.LessOrEqual(t, delay, policy.BaseBackoff)
+	})
+
+	t.Run("backs off exponentially with jitter, capped at MaxBackoff", func(t *testing.T) {
+		resp := response(http.StatusInternalServerError, nil)
+		for attempt := 0; attempt < 6; attempt++ {
+			backoff := policy.BaseBackoff << uint(attempt)
+			if backoff > policy.MaxBackoff {
+				backoff = policy.MaxBackoff
+			}
+			delay := retryDelay(resp, attempt, policy)
+			assert.GreaterOrEqual(t, delay, backoff/2)
+			assert.LessOrEqual(t, delay, backoff)
+		}
+	})
+}
+
+func response(status int, header http.Header) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{StatusCode: status, Header: header}
+}
+
+func formatEpoch(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}